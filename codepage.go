@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Codepage is a single-byte ASCII<->EBCDIC translation table.
+type Codepage struct {
+	Name      string
+	fromASCII [128]byte
+	toASCII   map[byte]byte
+}
+
+func newCodepage(name string, fromASCII [128]byte) Codepage {
+	toASCII := make(map[byte]byte, len(fromASCII))
+	for ascii, ebcdic := range fromASCII {
+		toASCII[ebcdic] = byte(ascii)
+	}
+	return Codepage{Name: name, fromASCII: fromASCII, toASCII: toASCII}
+}
+
+// ToEBCDIC translates an ASCII string to this codepage's EBCDIC bytes.
+func (c Codepage) ToEBCDIC(s string) (string, error) {
+	out := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b >= 128 {
+			return "", fmt.Errorf("%s: byte %#x is outside the mapped ASCII range", c.Name, b)
+		}
+		out[i] = c.fromASCII[b]
+	}
+	return string(out), nil
+}
+
+// FromEBCDIC translates this codepage's EBCDIC bytes back to ASCII.
+func (c Codepage) FromEBCDIC(s string) (string, error) {
+	out := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		a, ok := c.toASCII[b]
+		if !ok {
+			return "", fmt.Errorf("%s: EBCDIC byte %#x has no ASCII mapping", c.Name, b)
+		}
+		out[i] = a
+	}
+	return string(out), nil
+}
+
+// cp037FromASCII is the IBM CCSID 037 (US/Canada) single-byte EBCDIC table,
+// indexed by ASCII code point 0-127.
+var cp037FromASCII = [128]byte{
+	0x00, 0x01, 0x02, 0x03, 0x37, 0x2D, 0x2E, 0x2F, 0x16, 0x05, 0x25, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F,
+	0x10, 0x11, 0x12, 0x13, 0x3C, 0x3D, 0x32, 0x26, 0x18, 0x19, 0x3F, 0x27, 0x1C, 0x1D, 0x1E, 0x1F,
+	0x40, 0x5A, 0x7F, 0x7B, 0x5B, 0x6C, 0x50, 0x7D, 0x4D, 0x5D, 0x5C, 0x4E, 0x6B, 0x60, 0x4B, 0x61,
+	0xF0, 0xF1, 0xF2, 0xF3, 0xF4, 0xF5, 0xF6, 0xF7, 0xF8, 0xF9, 0x7A, 0x5E, 0x4C, 0x7E, 0x6E, 0x6F,
+	0x7C, 0xC1, 0xC2, 0xC3, 0xC4, 0xC5, 0xC6, 0xC7, 0xC8, 0xC9, 0xD1, 0xD2, 0xD3, 0xD4, 0xD5, 0xD6,
+	0xD7, 0xD8, 0xD9, 0xE2, 0xE3, 0xE4, 0xE5, 0xE6, 0xE7, 0xE8, 0xE9, 0xAD, 0xE0, 0xBD, 0x5F, 0x6D,
+	0x79, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96,
+	0x97, 0x98, 0x99, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7, 0xA8, 0xA9, 0xC0, 0x4F, 0xD0, 0xA1, 0x07,
+}
+
+// CP500 is not yet supported, a scope reduction from the original request
+// of "at least CP037 and CP500": while most of its table matches CP037, it
+// genuinely diverges on several punctuation code points (notably brackets
+// and a few currency/logical marks), and shipping an approximation would
+// silently corrupt those bytes on round-trip. Add it once we have an
+// authoritative IBM CDRA table to transcribe rather than guessing.
+var codepages = map[string]Codepage{
+	"cp037": newCodepage("cp037", cp037FromASCII),
+}
+
+// LookupCodepage resolves an --encoding value like "ebcdic-cp037" to its
+// Codepage. ok is false for "", "ascii", or an unrecognized codepage name.
+func LookupCodepage(encoding string) (cp Codepage, ok bool) {
+	name := strings.TrimPrefix(encoding, "ebcdic-")
+	cp, ok = codepages[name]
+	return cp, ok
+}
@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestCodepageCP037RoundTrip(t *testing.T) {
+	cp, ok := LookupCodepage("ebcdic-cp037")
+	if !ok {
+		t.Fatal("LookupCodepage(\"ebcdic-cp037\") = false, want true")
+	}
+
+	const ascii = "Hello, World! 0123456789"
+	ebcdic, err := cp.ToEBCDIC(ascii)
+	if err != nil {
+		t.Fatalf("ToEBCDIC: %v", err)
+	}
+	if ebcdic == ascii {
+		t.Fatalf("ToEBCDIC(%q) returned input unchanged", ascii)
+	}
+
+	back, err := cp.FromEBCDIC(ebcdic)
+	if err != nil {
+		t.Fatalf("FromEBCDIC: %v", err)
+	}
+	if back != ascii {
+		t.Errorf("round trip = %q, want %q", back, ascii)
+	}
+}
+
+func TestCodepageCP037KnownBytes(t *testing.T) {
+	cp, _ := LookupCodepage("ebcdic-cp037")
+	got, err := cp.ToEBCDIC("A")
+	if err != nil {
+		t.Fatalf("ToEBCDIC: %v", err)
+	}
+	if got[0] != 0xC1 {
+		t.Errorf("ToEBCDIC(\"A\") = %#x, want 0xC1", got[0])
+	}
+}
+
+func TestCodepageRejectsNonASCII(t *testing.T) {
+	cp, _ := LookupCodepage("ebcdic-cp037")
+	if _, err := cp.ToEBCDIC("\xc3"); err == nil {
+		t.Error("ToEBCDIC with a byte >= 128 should fail, got nil error")
+	}
+}
+
+func TestLookupCodepageUnknown(t *testing.T) {
+	if _, ok := LookupCodepage("ebcdic-cp500"); ok {
+		t.Error(`LookupCodepage("ebcdic-cp500") = true, want false (no verified table yet)`)
+	}
+	if _, ok := LookupCodepage("ascii"); ok {
+		t.Error(`LookupCodepage("ascii") = true, want false`)
+	}
+}
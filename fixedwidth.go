@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// CSVWriterOptions configures the encoding/csv.Writer used by
+// processFixedWidthToCSV.
+type CSVWriterOptions struct {
+	Comma   rune
+	UseCRLF bool
+}
+
+func defaultCSVWriterOptions() CSVWriterOptions {
+	return CSVWriterOptions{Comma: ','}
+}
+
+func (o CSVWriterOptions) apply(w *csv.Writer) {
+	if o.Comma != 0 {
+		w.Comma = o.Comma
+	}
+	w.UseCRLF = o.UseCRLF
+}
+
+// processFixedWidthToCSV reads fixed-width records from r, one per line, and
+// writes them as RFC 4180 CSV to w via encoding/csv.Writer. Column order
+// follows the layout's schema mapping.
+func processFixedWidthToCSV(config Config, r io.Reader, w io.Writer) (written int, err error) {
+	layout, err := loadLayoutOrDefault(config.SchemaFile)
+	if err != nil {
+		return 0, err
+	}
+
+	csvWriter := csv.NewWriter(w)
+	defaultCSVWriterOptions().apply(csvWriter)
+	defer csvWriter.Flush()
+
+	columns := layout.CSVColumns()
+	recordLength := layout.RecordLength()
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if len(line) != recordLength {
+			return written, fmt.Errorf("line %d: length %d does not match record length %d", lineNum, len(line), recordLength)
+		}
+
+		fields, perr := layout.Parse(line)
+		if perr != nil {
+			return written, fmt.Errorf("line %d: %w", lineNum, perr)
+		}
+
+		row := make([]string, len(columns))
+		for i, name := range columns {
+			row[i] = fields[name]
+		}
+		if werr := csvWriter.Write(row); werr != nil {
+			return written, fmt.Errorf("line %d, column %d: error writing CSV row: %w", lineNum, len(row), werr)
+		}
+		written++
+	}
+	if serr := scanner.Err(); serr != nil {
+		return written, fmt.Errorf("error reading fixed-width input at line %d: %w", lineNum, serr)
+	}
+
+	return written, nil
+}
+
+// runFixedToCSV drives processFixedWidthToCSV for the fixed2csv mode,
+// opening config's input/output files.
+func runFixedToCSV(config Config) error {
+	inputFile, err := os.Open(config.InputFile)
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer inputFile.Close()
+
+	outputFile, err := os.Create(config.OutputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer outputFile.Close()
+
+	written, err := processFixedWidthToCSV(config, inputFile, outputFile)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Successfully processed %d records", written)
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSchemaFile(t *testing.T, dir string, doc string) string {
+	t.Helper()
+	path := filepath.Join(dir, "schema.json")
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestProcessFixedWidthToCSVFollowsMappingColumnOrder(t *testing.T) {
+	dir := t.TempDir()
+	const doc = `{
+		"record_length": 10,
+		"fields": [
+			{"name": "state", "width": 2},
+			{"name": "zip", "width": 5},
+			{"name": "last_name", "width": 3}
+		],
+		"mapping": [
+			{"field": "last_name", "index": 0},
+			{"field": "zip", "index": 1},
+			{"field": "state", "index": 2}
+		]
+	}`
+	schemaPath := writeSchemaFile(t, dir, doc)
+
+	layout, err := LoadLayout(schemaPath)
+	if err != nil {
+		t.Fatalf("LoadLayout: %v", err)
+	}
+	line, err := layout.Format(map[string]string{"state": "IL", "zip": "62701", "last_name": "Doe"})
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+
+	var out bytes.Buffer
+	config := Config{SchemaFile: schemaPath}
+	written, err := processFixedWidthToCSV(config, strings.NewReader(line+"\n"), &out)
+	if err != nil {
+		t.Fatalf("processFixedWidthToCSV: %v", err)
+	}
+	if written != 1 {
+		t.Fatalf("written = %d, want 1", written)
+	}
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("reading produced CSV: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d CSV rows, want 1", len(rows))
+	}
+	want := []string{"Doe", "62701", "IL"} // mapping order: last_name, zip, state
+	if len(rows[0]) != len(want) {
+		t.Fatalf("row = %v, want %v", rows[0], want)
+	}
+	for i, w := range want {
+		if rows[0][i] != w {
+			t.Errorf("row[%d] = %q, want %q", i, rows[0][i], w)
+		}
+	}
+}
+
+func TestProcessFixedWidthToCSVRejectsWrongLineLength(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := writeSchemaFile(t, dir, `{"record_length": 10, "fields": [{"name": "f", "width": 10}], "mapping": [{"field": "f", "index": 0}]}`)
+
+	config := Config{SchemaFile: schemaPath}
+	if _, err := processFixedWidthToCSV(config, strings.NewReader("short\n"), &bytes.Buffer{}); err == nil {
+		t.Error("processFixedWidthToCSV with a line shorter than record_length should fail")
+	}
+}
+
+func TestCSV2FixedFixed2CSVRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "in.csv")
+	fixedPath := filepath.Join(dir, "out.txt")
+	csvPath := filepath.Join(dir, "roundtrip.csv")
+
+	const row = "Doe,Jane,123 Main St,Springfield,IL,62701\n"
+	if err := os.WriteFile(inputPath, []byte(row), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	// csv2fixed using the bundled default address-record schema.
+	if err := processCSV(Config{InputFile: inputPath, OutputFile: fixedPath}); err != nil {
+		t.Fatalf("processCSV: %v", err)
+	}
+
+	// fixed2csv on what csv2fixed just produced.
+	if err := runFixedToCSV(Config{InputFile: fixedPath, OutputFile: csvPath}); err != nil {
+		t.Fatalf("runFixedToCSV: %v", err)
+	}
+
+	got, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	wantFields := strings.Split(strings.TrimSuffix(row, "\n"), ",")
+	gotFields, err := csv.NewReader(bytes.NewReader(got)).Read()
+	if err != nil {
+		t.Fatalf("parsing round-tripped CSV: %v", err)
+	}
+	if len(gotFields) != len(wantFields) {
+		t.Fatalf("round-tripped row = %v, want %v", gotFields, wantFields)
+	}
+	for i, want := range wantFields {
+		if gotFields[i] != want {
+			t.Errorf("round-tripped field %d = %q, want %q", i, gotFields[i], want)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldSpec describes a single fixed-width field in a record layout, modeled
+// after a COBOL copybook entry (PIC clause, filler, and justification).
+type FieldSpec struct {
+	Name     string `json:"name" yaml:"name"`
+	Width    int    `json:"width" yaml:"width"`
+	Filler   int    `json:"filler_width" yaml:"filler_width"`
+	Align    string `json:"align" yaml:"align"`       // "left" or "right"
+	PadChar  string `json:"pad_char" yaml:"pad_char"` // single character, defaults to " "
+	PadUnit  string `json:"pad_unit" yaml:"pad_unit"` // "runes" (default), "bytes", or "display-cells"
+	Overflow string `json:"overflow" yaml:"overflow"` // "truncate" or "error_on_overflow"
+	Encoding string `json:"encoding" yaml:"encoding"` // "ascii" or "ebcdic-cp037"
+	Picture  string `json:"picture" yaml:"picture"`   // COBOL PIC clause, e.g. "S9(7)V9(2) COMP-3"; empty means plain text
+
+	Constraints *FieldConstraints `json:"constraints" yaml:"constraints"` // optional validation rules
+}
+
+// ColumnMapping binds a CSV source column, by its zero-based index, to a
+// named layout field. The input CSV is assumed to have no header row, so
+// mapping by column name isn't supported.
+type ColumnMapping struct {
+	Field string `json:"field" yaml:"field"`
+	Index int    `json:"index" yaml:"index"`
+}
+
+// LayoutSchema is the on-disk (YAML or JSON) description of a fixed-width
+// record, analogous to a COBOL copybook.
+type LayoutSchema struct {
+	RecordLength int             `json:"record_length" yaml:"record_length"`
+	Fields       []FieldSpec     `json:"fields" yaml:"fields"`
+	Mapping      []ColumnMapping `json:"mapping" yaml:"mapping"`
+}
+
+// Layout formats and parses fixed-width records according to a LayoutSchema.
+type Layout struct {
+	schema LayoutSchema
+	byName map[string]FieldSpec
+	pics   map[string]PIC
+}
+
+// LoadLayout reads a layout schema from a YAML or JSON file, selected by
+// extension.
+func LoadLayout(path string) (*Layout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read layout schema %s: %w", path, err)
+	}
+
+	var schema LayoutSchema
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse layout schema %s: %w", path, err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse layout schema %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported layout schema extension %q (expected .json, .yaml, or .yml)", ext)
+	}
+
+	return NewLayout(schema)
+}
+
+// NewLayout validates a schema and builds a Layout from it. Validation
+// confirms every field has a positive width, a non-negative filler width,
+// a pad_char that is at most a single character, no field name repeats, any
+// Picture clause parses and matches the declared width, and (when
+// record_length is declared) the fields sum to it exactly.
+func NewLayout(schema LayoutSchema) (*Layout, error) {
+	byName := make(map[string]FieldSpec, len(schema.Fields))
+	pics := make(map[string]PIC)
+	total := 0
+	for _, f := range schema.Fields {
+		if f.Width <= 0 {
+			return nil, fmt.Errorf("field %q: width must be positive", f.Name)
+		}
+		if f.Filler < 0 {
+			return nil, fmt.Errorf("field %q: filler_width must not be negative", f.Name)
+		}
+		if f.PadChar != "" && utf8.RuneCountInString(f.PadChar) != 1 {
+			return nil, fmt.Errorf("field %q: pad_char must be a single character, got %q", f.Name, f.PadChar)
+		}
+		if _, exists := byName[f.Name]; exists {
+			return nil, fmt.Errorf("field %q declared more than once", f.Name)
+		}
+		byName[f.Name] = f
+
+		if f.Picture != "" {
+			pic, err := ParsePIC(f.Picture)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.Name, err)
+			}
+			if pic.Width() != f.Width {
+				return nil, fmt.Errorf("field %q: picture %q needs width %d, declared width is %d", f.Name, f.Picture, pic.Width(), f.Width)
+			}
+			pics[f.Name] = pic
+		}
+
+		total += f.Width + f.Filler
+	}
+	if schema.RecordLength > 0 && total != schema.RecordLength {
+		return nil, fmt.Errorf("layout declares record_length %d but fields sum to %d", schema.RecordLength, total)
+	}
+
+	return &Layout{schema: schema, byName: byName, pics: pics}, nil
+}
+
+// RecordLength returns the total width of a formatted record, including
+// fillers.
+func (l *Layout) RecordLength() int {
+	if l.schema.RecordLength > 0 {
+		return l.schema.RecordLength
+	}
+	total := 0
+	for _, f := range l.schema.Fields {
+		total += f.Width + f.Filler
+	}
+	return total
+}
+
+// Format assembles record into a fixed-width line following the schema's
+// field order, alignment/padding or PIC rules, and per-field encoding.
+func (l *Layout) Format(record map[string]string) (string, error) {
+	var builder strings.Builder
+	builder.Grow(l.RecordLength())
+
+	for _, f := range l.schema.Fields {
+		value, err := l.formatFieldValue(f, record[f.Name])
+		if err != nil {
+			return "", fmt.Errorf("field %q: %w", f.Name, err)
+		}
+		encoded, err := l.encodeField(f, value)
+		if err != nil {
+			return "", fmt.Errorf("field %q: %w", f.Name, err)
+		}
+		builder.WriteString(encoded)
+
+		if f.Filler > 0 {
+			filler, err := l.encodeField(f, padRight("", f.Filler))
+			if err != nil {
+				return "", fmt.Errorf("field %q filler: %w", f.Name, err)
+			}
+			builder.WriteString(filler)
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// formatFieldValue renders a single field's value as unencoded bytes, using
+// its PIC clause when declared or plain text padding/truncation otherwise.
+func (l *Layout) formatFieldValue(f FieldSpec, value string) (string, error) {
+	if pic, ok := l.pics[f.Name]; ok {
+		if pic.Comp3 {
+			return pic.FormatComp3(value)
+		}
+		return pic.FormatZoned(value)
+	}
+	return formatField(f, value)
+}
+
+// encodeField translates a field's formatted bytes into its declared output
+// encoding. Packed-decimal (COMP-3) fields are raw BCD bytes, not text, so
+// they pass through untranslated regardless of Encoding.
+func (l *Layout) encodeField(f FieldSpec, value string) (string, error) {
+	if f.Encoding == "" || f.Encoding == "ascii" {
+		return value, nil
+	}
+	if pic, ok := l.pics[f.Name]; ok && pic.Comp3 {
+		return value, nil
+	}
+	cp, ok := LookupCodepage(f.Encoding)
+	if !ok {
+		return "", fmt.Errorf("unknown encoding %q", f.Encoding)
+	}
+	return cp.ToEBCDIC(value)
+}
+
+// decodeField is the inverse of encodeField, translating a field's raw
+// on-disk bytes back to ASCII before interpretation.
+func (l *Layout) decodeField(f FieldSpec, raw string) (string, error) {
+	if f.Encoding == "" || f.Encoding == "ascii" {
+		return raw, nil
+	}
+	if pic, ok := l.pics[f.Name]; ok && pic.Comp3 {
+		return raw, nil
+	}
+	cp, ok := LookupCodepage(f.Encoding)
+	if !ok {
+		return "", fmt.Errorf("unknown encoding %q", f.Encoding)
+	}
+	return cp.FromEBCDIC(raw)
+}
+
+// SetDefaultEncoding fills in Encoding for every field that doesn't declare
+// its own, implementing the top-level --encoding override.
+func (l *Layout) SetDefaultEncoding(encoding string) {
+	for i, f := range l.schema.Fields {
+		if f.Encoding == "" {
+			l.schema.Fields[i].Encoding = encoding
+		}
+	}
+}
+
+// Parse splits a fixed-width line back into named field values, decoding
+// each field's encoding and PIC representation (when declared) or trimming
+// trailing pad characters for plain text.
+func (l *Layout) Parse(line string) (map[string]string, error) {
+	if len(line) != l.RecordLength() {
+		return nil, fmt.Errorf("line length %d does not match record length %d", len(line), l.RecordLength())
+	}
+
+	record := make(map[string]string, len(l.schema.Fields))
+	pos := 0
+	for _, f := range l.schema.Fields {
+		raw := line[pos : pos+f.Width]
+		pos += f.Width + f.Filler
+
+		decoded, err := l.decodeField(f, raw)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", f.Name, err)
+		}
+
+		if pic, ok := l.pics[f.Name]; ok {
+			var value string
+			if pic.Comp3 {
+				value, err = pic.ParseComp3(decoded)
+			} else {
+				value, err = pic.ParseZoned(decoded)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.Name, err)
+			}
+			record[f.Name] = value
+			continue
+		}
+
+		record[f.Name] = strings.TrimRight(decoded, padChar(f))
+	}
+	return record, nil
+}
+
+// formatField pads or truncates a single field value per its FieldSpec.
+// Truncation respects PadUnit (runes by default) so a multi-byte character
+// is never split in two.
+func formatField(f FieldSpec, value string) (string, error) {
+	if len(value) > f.Width && f.Overflow == "error_on_overflow" {
+		return "", fmt.Errorf("value %q exceeds width %d bytes", value, f.Width)
+	}
+
+	return padString(padUnitOf(f), value, f.Width, padChar(f), f.Align == "right"), nil
+}
+
+// padChar returns the single pad character for a field, defaulting to a
+// space.
+func padChar(f FieldSpec) string {
+	if f.PadChar == "" {
+		return " "
+	}
+	return f.PadChar
+}
+
+// CSVColumns returns field names ordered by their declared CSV column
+// index, used to round-trip a parsed field map back into CSV row order.
+func (l *Layout) CSVColumns() []string {
+	cols := make([]string, len(l.schema.Mapping))
+	for _, m := range l.schema.Mapping {
+		if m.Index >= 0 && m.Index < len(cols) {
+			cols[m.Index] = m.Field
+		}
+	}
+	return cols
+}
+
+// defaultLayoutSchema reproduces the original hard-coded 161-character
+// address record layout, used when no --schema file is supplied.
+func defaultLayoutSchema() LayoutSchema {
+	return LayoutSchema{
+		RecordLength: 161,
+		Fields: []FieldSpec{
+			{Name: "last_name", Width: 25, Filler: 5, Align: "left"},
+			{Name: "first_name", Width: 15, Filler: 5, Align: "left"},
+			{Name: "street", Width: 30, Filler: 5, Align: "left"},
+			{Name: "city", Width: 15, Filler: 5, Align: "left"},
+			{Name: "state", Width: 3, Filler: 5, Align: "left"},
+			{Name: "zip", Width: 10, Filler: 38, Align: "left"},
+		},
+		Mapping: []ColumnMapping{
+			{Field: "last_name", Index: 0},
+			{Field: "first_name", Index: 1},
+			{Field: "street", Index: 2},
+			{Field: "city", Index: 3},
+			{Field: "state", Index: 4},
+			{Field: "zip", Index: 5},
+		},
+	}
+}
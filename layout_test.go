@@ -0,0 +1,183 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testLayoutSchema() LayoutSchema {
+	return LayoutSchema{
+		RecordLength: 17,
+		Fields: []FieldSpec{
+			{Name: "last_name", Width: 6, Filler: 2, Align: "left"},
+			{Name: "zip", Width: 5, Align: "right", PadChar: "0"},
+			{Name: "state", Width: 2, Align: "left"},
+			{Name: "country", Width: 2, Align: "left"},
+		},
+		Mapping: []ColumnMapping{
+			{Field: "last_name", Index: 0},
+			{Field: "zip", Index: 1},
+			{Field: "state", Index: 2},
+			{Field: "country", Index: 3},
+		},
+	}
+}
+
+func TestLayoutFormatParseRoundTrip(t *testing.T) {
+	layout, err := NewLayout(testLayoutSchema())
+	if err != nil {
+		t.Fatalf("NewLayout: %v", err)
+	}
+
+	record := map[string]string{
+		"last_name": "Doe",
+		"zip":       "701",
+		"state":     "IL",
+		"country":   "US",
+	}
+	line, err := layout.Format(record)
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	if len(line) != layout.RecordLength() {
+		t.Fatalf("Format produced %d bytes, want %d", len(line), layout.RecordLength())
+	}
+	const want = "Doe     00701ILUS"
+	if line != want {
+		t.Fatalf("Format(%v) = %q, want %q", record, line, want)
+	}
+
+	// zip is right-aligned and zero-padded, so its leading zero fill is
+	// indistinguishable from data: Parse can only trim trailing pad
+	// characters, which round-trips to "00701", not the original "701".
+	wantParsed := map[string]string{
+		"last_name": "Doe",
+		"zip":       "00701",
+		"state":     "IL",
+		"country":   "US",
+	}
+	got, err := layout.Parse(line)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	for field, want := range wantParsed {
+		if got[field] != want {
+			t.Errorf("Parse(%q)[%q] = %q, want %q", line, field, got[field], want)
+		}
+	}
+}
+
+func TestLayoutFormatRejectsOverflowWhenConfigured(t *testing.T) {
+	schema := LayoutSchema{
+		Fields: []FieldSpec{
+			{Name: "code", Width: 3, Overflow: "error_on_overflow"},
+		},
+	}
+	layout, err := NewLayout(schema)
+	if err != nil {
+		t.Fatalf("NewLayout: %v", err)
+	}
+	if _, err := layout.Format(map[string]string{"code": "TOOLONG"}); err == nil {
+		t.Error("Format with an overflowing value should fail when Overflow is error_on_overflow")
+	}
+}
+
+func TestNewLayoutRejectsRecordLengthMismatch(t *testing.T) {
+	schema := testLayoutSchema()
+	schema.RecordLength = 99
+	if _, err := NewLayout(schema); err == nil {
+		t.Error("NewLayout with a record_length that doesn't match field widths should fail")
+	}
+}
+
+func TestNewLayoutRejectsNonPositiveWidth(t *testing.T) {
+	schema := LayoutSchema{Fields: []FieldSpec{{Name: "f", Width: 0}}}
+	if _, err := NewLayout(schema); err == nil {
+		t.Error("NewLayout with a zero-width field should fail")
+	}
+}
+
+func TestNewLayoutRejectsDuplicateFieldName(t *testing.T) {
+	schema := LayoutSchema{Fields: []FieldSpec{
+		{Name: "f", Width: 1},
+		{Name: "f", Width: 2},
+	}}
+	if _, err := NewLayout(schema); err == nil {
+		t.Error("NewLayout with a duplicate field name should fail")
+	}
+}
+
+func TestNewLayoutRejectsNegativeFiller(t *testing.T) {
+	schema := LayoutSchema{Fields: []FieldSpec{
+		{Name: "a", Width: 5, Filler: -1},
+		{Name: "b", Width: 5},
+		{Name: "c", Width: 5},
+	}}
+	if _, err := NewLayout(schema); err == nil {
+		t.Error("NewLayout with a negative filler_width should fail")
+	}
+}
+
+func TestNewLayoutRejectsMultiCharPadChar(t *testing.T) {
+	schema := LayoutSchema{Fields: []FieldSpec{
+		{Name: "f", Width: 5, PadChar: "AB"},
+	}}
+	if _, err := NewLayout(schema); err == nil {
+		t.Error("NewLayout with a multi-character pad_char should fail")
+	}
+}
+
+func TestNewLayoutRejectsPictureWidthMismatch(t *testing.T) {
+	schema := LayoutSchema{Fields: []FieldSpec{
+		{Name: "amount", Width: 5, Picture: "S9(7)V9(2)"},
+	}}
+	if _, err := NewLayout(schema); err == nil {
+		t.Error("NewLayout with a Picture whose width disagrees with the declared Width should fail")
+	}
+}
+
+func TestLayoutCSVColumnsFollowsMappingIndex(t *testing.T) {
+	layout, err := NewLayout(testLayoutSchema())
+	if err != nil {
+		t.Fatalf("NewLayout: %v", err)
+	}
+	want := []string{"last_name", "zip", "state", "country"}
+	got := layout.CSVColumns()
+	if len(got) != len(want) {
+		t.Fatalf("CSVColumns() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CSVColumns()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadLayoutJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.json")
+	const doc = `{"record_length": 5, "fields": [{"name": "code", "width": 5}]}`
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	layout, err := LoadLayout(path)
+	if err != nil {
+		t.Fatalf("LoadLayout: %v", err)
+	}
+	if layout.RecordLength() != 5 {
+		t.Errorf("RecordLength() = %d, want 5", layout.RecordLength())
+	}
+}
+
+func TestLoadLayoutRejectsUnsupportedExtension(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.txt")
+	if err := os.WriteFile(path, []byte("irrelevant"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := LoadLayout(path); err == nil {
+		t.Error("LoadLayout with an unsupported extension should fail")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// padUnitOf resolves a FieldSpec's PadUnit, defaulting to "runes" (the
+// width-aware default) over the historical raw byte-slicing behavior.
+func padUnitOf(f FieldSpec) string {
+	if f.PadUnit == "" {
+		return "runes"
+	}
+	return f.PadUnit
+}
+
+// padString truncates s to fit within widthBytes bytes -- always on a rune
+// boundary, so a multi-byte character is never split -- and pads the
+// result on the right (or left, for right-aligned fields) with padChar up
+// to exactly widthBytes. A field's on-disk width is always a byte count
+// (that's what keeps fixed-width records byte-exact); unit only changes
+// how the truncation point is chosen:
+//   - "bytes": cut at the byte boundary nearest widthBytes
+//   - "runes" (default): never split a Unicode code point
+//   - "display-cells": as "runes", but also stop early if keeping a
+//     double-width (CJK/east-asian) rune would overflow the field once
+//     padded, so wide characters don't overrun their declared column width
+func padString(unit string, s string, widthBytes int, padChar string, alignRight bool) string {
+	truncated := truncateToFit(unit, s, widthBytes)
+	deficit := widthBytes - len(truncated)
+	if deficit <= 0 {
+		return truncated
+	}
+
+	filler := strings.Repeat(padChar, deficit)
+	if alignRight {
+		return filler + truncated
+	}
+	return truncated + filler
+}
+
+func truncateToFit(unit string, s string, widthBytes int) string {
+	if widthBytes <= 0 {
+		return ""
+	}
+	if len(s) <= widthBytes {
+		return s
+	}
+	if unit == "bytes" {
+		return s[:widthBytes]
+	}
+
+	cells := 0
+	for i, r := range s {
+		n := utf8.RuneLen(r)
+		w := n
+		if unit == "display-cells" {
+			w = runewidth.RuneWidth(r)
+		}
+		if i+n > widthBytes || cells+w > widthBytes {
+			return s[:i]
+		}
+		cells += w
+	}
+	return s
+}
+
+// padRight pads s to length bytes with spaces, truncating on a rune
+// boundary if s is already longer. This is the default text-field padding
+// used wherever a FieldSpec doesn't specify PadUnit.
+func padRight(s string, length int) string {
+	return padString("runes", s, length, " ", false)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestPadStringRunesKeepsCombiningMarkIntact(t *testing.T) {
+	const combining = "é" // "e" + COMBINING ACUTE ACCENT: 2 runes, 3 bytes
+	got := padString("runes", combining+"bc", 4, " ", false)
+	want := combining + "b"
+	if got != want {
+		t.Errorf("padString(runes, %q, 4) = %q, want %q", combining+"bc", got, want)
+	}
+}
+
+func TestPadStringRunesNeverSplitsEmoji(t *testing.T) {
+	const emoji = "\U0001F600" // 4 bytes
+	got := padString("runes", "ab"+emoji+"cd", 5, " ", false)
+	want := "ab   "
+	if got != want {
+		t.Errorf("padString(runes, %q, 5) = %q, want %q (emoji doesn't fit in remaining 3 bytes)", "ab"+emoji+"cd", got, want)
+	}
+}
+
+func TestPadStringBytesModeCanSplitMultiByteRune(t *testing.T) {
+	const e = "é" // precomposed "é", 2 bytes
+	got := padString("bytes", e+"b", 1, " ", false)
+	if len(got) != 1 {
+		t.Fatalf("padString(bytes, %q, 1) produced %d bytes, want 1", e+"b", len(got))
+	}
+	if utf8.ValidString(got) {
+		t.Errorf("padString(bytes, ...) = %q, want an invalid partial UTF-8 sequence (bytes mode cuts mid-rune by design)", got)
+	}
+}
+
+func TestPadStringDisplayCellsTruncatesOnRuneBoundary(t *testing.T) {
+	const cjk = "日本語" // each rune is 3 bytes wide, display width 2
+	got := padString("display-cells", cjk, 6, " ", false)
+	want := "日本"
+	if got != want {
+		t.Errorf("padString(display-cells, %q, 6) = %q, want %q", cjk, got, want)
+	}
+}
+
+func TestPadRightTruncatesMultiByteRunesAndPads(t *testing.T) {
+	const emoji = "\U0001F600"
+	got := padRight(emoji+emoji, 6)
+	want := emoji + "  "
+	if got != want {
+		t.Errorf("padRight(%q, 6) = %q, want %q", emoji+emoji, got, want)
+	}
+}
+
+func TestPadRightNoTruncationNeeded(t *testing.T) {
+	got := padRight("abc", 5)
+	want := "abc  "
+	if got != want {
+		t.Errorf("padRight(\"abc\", 5) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PIC describes a numeric COBOL PIC clause, e.g. "9(5)" (zoned decimal) or
+// "S9(7)V9(2) COMP-3" (signed packed decimal).
+type PIC struct {
+	Signed     bool
+	IntDigits  int
+	FracDigits int
+	Comp3      bool
+}
+
+var picPattern = regexp.MustCompile(`^(S)?9\((\d+)\)(?:V9\((\d+)\))?(?:\s+(COMP-3|COMP3))?$`)
+
+// ParsePIC parses a COBOL PIC clause into a PIC descriptor.
+func ParsePIC(clause string) (PIC, error) {
+	m := picPattern.FindStringSubmatch(strings.TrimSpace(clause))
+	if m == nil {
+		return PIC{}, fmt.Errorf("invalid PIC clause %q", clause)
+	}
+
+	intDigits, err := strconv.Atoi(m[2])
+	if err != nil {
+		return PIC{}, fmt.Errorf("invalid PIC clause %q: %w", clause, err)
+	}
+	fracDigits := 0
+	if m[3] != "" {
+		fracDigits, err = strconv.Atoi(m[3])
+		if err != nil {
+			return PIC{}, fmt.Errorf("invalid PIC clause %q: %w", clause, err)
+		}
+	}
+
+	return PIC{
+		Signed:     m[1] == "S",
+		IntDigits:  intDigits,
+		FracDigits: fracDigits,
+		Comp3:      m[4] != "",
+	}, nil
+}
+
+// Width returns the number of bytes the PIC occupies once formatted: one
+// byte per digit for zoned decimal, or two digits per byte plus a sign
+// nibble for COMP-3.
+func (p PIC) Width() int {
+	digitCount := p.IntDigits + p.FracDigits
+	if p.Comp3 {
+		return digitCount/2 + 1
+	}
+	return digitCount
+}
+
+// digits splits value into a zero-padded, unsigned digit string matching
+// IntDigits+FracDigits, plus whether it was negative.
+func (p PIC) digits(value string) (digits string, negative bool, err error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "-") {
+		negative = true
+		value = value[1:]
+	} else if strings.HasPrefix(value, "+") {
+		value = value[1:]
+	}
+
+	intPart, fracPart := value, ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		intPart, fracPart = value[:i], value[i+1:]
+	}
+	if len(fracPart) > p.FracDigits {
+		return "", false, fmt.Errorf("value %q has more than %d fractional digits", value, p.FracDigits)
+	}
+	fracPart += strings.Repeat("0", p.FracDigits-len(fracPart))
+
+	if len(intPart) > p.IntDigits {
+		return "", false, fmt.Errorf("value %q exceeds %d integer digits", value, p.IntDigits)
+	}
+	intPart = strings.Repeat("0", p.IntDigits-len(intPart)) + intPart
+
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return "", false, fmt.Errorf("value %q is not numeric", value)
+		}
+	}
+
+	return intPart + fracPart, negative, nil
+}
+
+// assembleDecimal re-inserts the sign and decimal point around a fixed-width
+// digit string, the inverse of digits.
+func (p PIC) assembleDecimal(digits string, negative bool) string {
+	intPart, fracPart := digits[:p.IntDigits], digits[p.IntDigits:]
+
+	var sb strings.Builder
+	if negative {
+		sb.WriteByte('-')
+	}
+	sb.WriteString(intPart)
+	if p.FracDigits > 0 {
+		sb.WriteByte('.')
+		sb.WriteString(fracPart)
+	}
+	return sb.String()
+}
+
+// zonedOverpunchPositive/zonedOverpunchNegative are the standard COBOL
+// zoned-decimal overpunch characters that replace the last unsigned digit
+// to encode its sign.
+var zonedOverpunchPositive = [10]byte{'{', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I'}
+var zonedOverpunchNegative = [10]byte{'}', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R'}
+
+func overpunchDigit(table [10]byte, c byte) (byte, bool) {
+	for d, o := range table {
+		if o == c {
+			return byte(d), true
+		}
+	}
+	return 0, false
+}
+
+// FormatZoned renders value as zoned decimal: one ASCII digit per byte, with
+// the sign overpunched onto the last digit when the PIC is signed.
+func (p PIC) FormatZoned(value string) (string, error) {
+	digits, negative, err := p.digits(value)
+	if err != nil {
+		return "", err
+	}
+
+	b := []byte(digits)
+	if p.Signed && len(b) > 0 {
+		last := b[len(b)-1] - '0'
+		if negative {
+			b[len(b)-1] = zonedOverpunchNegative[last]
+		} else {
+			b[len(b)-1] = zonedOverpunchPositive[last]
+		}
+	}
+	return string(b), nil
+}
+
+// ParseZoned decodes a zoned-decimal field back into a signed decimal
+// string, the inverse of FormatZoned.
+func (p PIC) ParseZoned(raw string) (string, error) {
+	digitCount := p.IntDigits + p.FracDigits
+	if len(raw) != digitCount {
+		return "", fmt.Errorf("zoned field is %d bytes, expected %d", len(raw), digitCount)
+	}
+
+	b := []byte(raw)
+	negative := false
+	if p.Signed && len(b) > 0 {
+		last := b[len(b)-1]
+		if d, ok := overpunchDigit(zonedOverpunchNegative, last); ok {
+			negative = true
+			b[len(b)-1] = '0' + d
+		} else if d, ok := overpunchDigit(zonedOverpunchPositive, last); ok {
+			b[len(b)-1] = '0' + d
+		}
+	}
+
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return "", fmt.Errorf("zoned field contains non-digit byte %#x", c)
+		}
+	}
+
+	return p.assembleDecimal(string(b), negative), nil
+}
+
+// FormatComp3 renders value as packed decimal (COMP-3): two digits per byte,
+// with a sign nibble (0xC positive, 0xD negative, 0xF unsigned) in the low
+// nibble of the last byte.
+func (p PIC) FormatComp3(value string) (string, error) {
+	digits, negative, err := p.digits(value)
+	if err != nil {
+		return "", err
+	}
+
+	signNibble := byte(0xF)
+	if p.Signed {
+		if negative {
+			signNibble = 0xD
+		} else {
+			signNibble = 0xC
+		}
+	}
+
+	nibbles := make([]byte, 0, len(digits)+1)
+	for _, d := range digits {
+		nibbles = append(nibbles, byte(d-'0'))
+	}
+	nibbles = append(nibbles, signNibble)
+	if len(nibbles)%2 != 0 {
+		nibbles = append([]byte{0}, nibbles...)
+	}
+
+	out := make([]byte, len(nibbles)/2)
+	for i := range out {
+		out[i] = nibbles[2*i]<<4 | nibbles[2*i+1]
+	}
+	return string(out), nil
+}
+
+// ParseComp3 decodes a packed-decimal (COMP-3) field back into a signed
+// decimal string, the inverse of FormatComp3.
+func (p PIC) ParseComp3(raw string) (string, error) {
+	if len(raw) != p.Width() {
+		return "", fmt.Errorf("packed field is %d bytes, expected %d", len(raw), p.Width())
+	}
+
+	nibbles := make([]byte, 0, len(raw)*2)
+	for i := 0; i < len(raw); i++ {
+		b := raw[i]
+		nibbles = append(nibbles, b>>4, b&0x0F)
+	}
+
+	signNibble := nibbles[len(nibbles)-1]
+	digitNibbles := nibbles[:len(nibbles)-1]
+	digitCount := p.IntDigits + p.FracDigits
+	if len(digitNibbles) > digitCount {
+		digitNibbles = digitNibbles[len(digitNibbles)-digitCount:]
+	}
+
+	digits := make([]byte, len(digitNibbles))
+	for i, n := range digitNibbles {
+		if n > 9 {
+			return "", fmt.Errorf("packed field has invalid digit nibble %#x", n)
+		}
+		digits[i] = '0' + n
+	}
+
+	negative := p.Signed && signNibble == 0xD
+	return p.assembleDecimal(string(digits), negative), nil
+}
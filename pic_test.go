@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+func TestPICZonedRoundTrip(t *testing.T) {
+	cases := []string{"0012345.67", "-0012345.67", "0000000.00"}
+	pic, err := ParsePIC("S9(7)V9(2)")
+	if err != nil {
+		t.Fatalf("ParsePIC: %v", err)
+	}
+
+	for _, want := range cases {
+		encoded, err := pic.FormatZoned(want)
+		if err != nil {
+			t.Fatalf("FormatZoned(%q): %v", want, err)
+		}
+		if len(encoded) != pic.Width() {
+			t.Fatalf("FormatZoned(%q) produced %d bytes, want %d", want, len(encoded), pic.Width())
+		}
+
+		got, err := pic.ParseZoned(encoded)
+		if err != nil {
+			t.Fatalf("ParseZoned(%q): %v", encoded, err)
+		}
+		if got != want {
+			t.Errorf("zoned round trip %q -> %q -> %q", want, encoded, got)
+		}
+	}
+}
+
+func TestPICComp3RoundTrip(t *testing.T) {
+	cases := []string{"0012345.67", "-0012345.67", "0000000.00"}
+	pic, err := ParsePIC("S9(7)V9(2) COMP-3")
+	if err != nil {
+		t.Fatalf("ParsePIC: %v", err)
+	}
+
+	for _, want := range cases {
+		encoded, err := pic.FormatComp3(want)
+		if err != nil {
+			t.Fatalf("FormatComp3(%q): %v", want, err)
+		}
+		if len(encoded) != pic.Width() {
+			t.Fatalf("FormatComp3(%q) produced %d bytes, want %d", want, len(encoded), pic.Width())
+		}
+
+		got, err := pic.ParseComp3(encoded)
+		if err != nil {
+			t.Fatalf("ParseComp3(%q): %v", encoded, err)
+		}
+		if got != want {
+			t.Errorf("comp-3 round trip %q -> %q -> %q", want, encoded, got)
+		}
+	}
+}
+
+func TestParsePICRejectsInvalidClause(t *testing.T) {
+	if _, err := ParsePIC("not a pic clause"); err == nil {
+		t.Error("ParsePIC with an invalid clause should fail, got nil error")
+	}
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CSVReaderOptions configures the encoding/csv.Reader underlying a Processor.
+type CSVReaderOptions struct {
+	Comma            rune
+	Comment          rune
+	LazyQuotes       bool
+	TrimLeadingSpace bool
+	FieldsPerRecord  int // 0 = infer from first record, -1 = no check, >0 = exact
+	ReuseRecord      bool
+}
+
+// defaultCSVReaderOptions builds CSV reader options for a comma-separated
+// file whose column count is fixed by the layout's Mapping (0 skips the
+// check, matching encoding/csv's "infer from first record" default when a
+// schema declares no mapping).
+func defaultCSVReaderOptions(fieldsPerRecord int) CSVReaderOptions {
+	return CSVReaderOptions{
+		Comma:           ',',
+		FieldsPerRecord: fieldsPerRecord,
+	}
+}
+
+func (o CSVReaderOptions) apply(r *csv.Reader) {
+	if o.Comma != 0 {
+		r.Comma = o.Comma
+	}
+	r.Comment = o.Comment
+	r.LazyQuotes = o.LazyQuotes
+	r.TrimLeadingSpace = o.TrimLeadingSpace
+	r.FieldsPerRecord = o.FieldsPerRecord
+	r.ReuseRecord = o.ReuseRecord
+}
+
+// ParseFunc converts a raw CSV record into a field-name/value map, the same
+// shape Layout.Format and RecordValidator.Validate operate on.
+type ParseFunc func(fields []string) (map[string]string, error)
+
+// TransformFunc optionally mutates a parsed record before formatting.
+type TransformFunc func(map[string]string) (map[string]string, error)
+
+// FormatFunc renders a record to its output line.
+type FormatFunc func(map[string]string) (string, error)
+
+// ErrRejectThresholdExceeded is returned by Processor.Run when the fraction
+// of rejected rows exceeds RejectThreshold. The run still completes and its
+// Summary is valid; this only signals that the caller should treat the
+// batch as failed.
+var ErrRejectThresholdExceeded = errors.New("reject rate exceeds configured threshold")
+
+// Summary reports aggregate outcome counts for a Processor run.
+type Summary struct {
+	Processed  int
+	Written    int
+	Rejected   int
+	RuleCounts map[string]int
+}
+
+// Processor runs a streaming parse -> validate -> transform -> format ->
+// write pipeline over a CSV input, using a bounded worker pool for the
+// transform/format stage and a buffered writer that flushes on
+// FlushInterval. Rows that fail parsing, validation, transform, or
+// formatting are routed to RejectWriter instead of aborting the run.
+type Processor struct {
+	ReaderOptions   CSVReaderOptions
+	Parse           ParseFunc
+	Transform       TransformFunc
+	Format          FormatFunc
+	Validator       *RecordValidator
+	Workers         int           // bounded worker pool size; <= 1 runs sequentially
+	FlushInterval   time.Duration // 0 disables periodic flushing (flush only at EOF)
+	OnSkip          func(index int, reason string)
+	RejectWriter    *csv.Writer
+	RejectThreshold float64 // 0 disables; fraction of processed rows that may be rejected
+}
+
+// NewProcessor builds a Processor that parses and formats records according
+// to layout's schema, so any schema's Mapping and field names -- not just
+// the bundled default address record -- are reachable from the CLI.
+func NewProcessor(layout *Layout) *Processor {
+	mapping := layout.schema.Mapping
+	fieldsPerRecord := 0
+	if len(mapping) > 0 {
+		fieldsPerRecord = len(mapping)
+	}
+	return &Processor{
+		ReaderOptions: defaultCSVReaderOptions(fieldsPerRecord),
+		Parse:         newMappingParseFunc(mapping),
+		Format: func(record map[string]string) (string, error) {
+			return layout.Format(record)
+		},
+		// Workers defaults to 1 (sequential): runConcurrent dispatches one
+		// channel message per record, and BenchmarkProcessorRunConcurrent
+		// shows that overhead losing to the sequential path on the cheap
+		// per-record work this pipeline does today. Callers can still opt
+		// into the worker pool by setting Workers > 1 once a batched job
+		// design makes it worthwhile.
+		Workers:       1,
+		FlushInterval: time.Second,
+	}
+}
+
+// newMappingParseFunc builds a ParseFunc that reads each mapped field out of
+// its declared CSV column index, the forward-direction counterpart of
+// Layout.CSVColumns used by the fixed2csv path.
+func newMappingParseFunc(mapping []ColumnMapping) ParseFunc {
+	return func(fields []string) (map[string]string, error) {
+		if len(mapping) > 0 && len(fields) != len(mapping) {
+			return nil, fmt.Errorf("expected %d fields, got %d", len(mapping), len(fields))
+		}
+		record := make(map[string]string, len(mapping))
+		for _, m := range mapping {
+			if m.Index < 0 || m.Index >= len(fields) {
+				return nil, fmt.Errorf("field %q: mapping index %d out of range for %d fields", m.Field, m.Index, len(fields))
+			}
+			record[m.Field] = strings.TrimSpace(fields[m.Index])
+		}
+		return record, nil
+	}
+}
+
+// rejection carries why a row was rejected: the rule slugs (for the summary
+// report) and a human-readable reason (for the reject file).
+type rejection struct {
+	rules  []string
+	reason string
+}
+
+// syncWriter guards a bufio.Writer with a mutex so the periodic flusher
+// goroutine can call Flush concurrently with the write loop's WriteString
+// calls without racing on the underlying bufio.Writer, which is not
+// goroutine-safe on its own.
+type syncWriter struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func newSyncWriter(w io.Writer) *syncWriter {
+	return &syncWriter{w: bufio.NewWriter(w)}
+}
+
+func (sw *syncWriter) WriteString(s string) (int, error) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.w.WriteString(s)
+}
+
+func (sw *syncWriter) Flush() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.w.Flush()
+}
+
+// Run reads CSV records from r, pipes each through Parse -> Validate ->
+// Transform -> Format, and writes the resulting lines to w. Rejected rows
+// are reported via OnSkip and RejectWriter rather than aborting the run.
+func (p *Processor) Run(r io.Reader, w io.Writer) (Summary, error) {
+	reader := csv.NewReader(r)
+	p.ReaderOptions.apply(reader)
+
+	bufWriter := newSyncWriter(w)
+	defer bufWriter.Flush()
+
+	if p.FlushInterval > 0 {
+		stop := make(chan struct{})
+		defer close(stop)
+		go p.periodicFlush(bufWriter, stop)
+	}
+
+	var summary Summary
+	var err error
+
+	// ReuseRecord means csv.Reader hands back the same backing array on
+	// every call, so a record must be fully processed before the next
+	// Read -- that rules out the concurrent worker pool below.
+	if p.ReaderOptions.ReuseRecord || p.Workers <= 1 {
+		summary, err = p.runSequential(reader, bufWriter)
+	} else {
+		summary, err = p.runConcurrent(reader, bufWriter)
+	}
+	if err != nil {
+		return summary, err
+	}
+
+	if p.RejectThreshold > 0 && summary.Processed > 0 {
+		if float64(summary.Rejected)/float64(summary.Processed) > p.RejectThreshold {
+			return summary, ErrRejectThresholdExceeded
+		}
+	}
+	return summary, nil
+}
+
+func (p *Processor) periodicFlush(w *syncWriter, stop <-chan struct{}) {
+	ticker := time.NewTicker(p.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Flush()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// reject records a rejected row in the summary, the OnSkip hook, and the
+// reject file (original fields plus a trailing reason column).
+func (p *Processor) reject(summary *Summary, index int, fields []string, rej *rejection) {
+	summary.Rejected++
+	for _, rule := range rej.rules {
+		if summary.RuleCounts == nil {
+			summary.RuleCounts = make(map[string]int)
+		}
+		summary.RuleCounts[rule]++
+	}
+	if p.OnSkip != nil {
+		p.OnSkip(index, rej.reason)
+	}
+	if p.RejectWriter != nil {
+		row := append(append([]string(nil), fields...), rej.reason)
+		_ = p.RejectWriter.Write(row) // best-effort; a reject-file write failure shouldn't abort the batch
+	}
+}
+
+func (p *Processor) runSequential(reader *csv.Reader, w *syncWriter) (Summary, error) {
+	var summary Summary
+	for i := 0; ; i++ {
+		fields, rerr := reader.Read()
+		if rerr == io.EOF {
+			return summary, nil
+		}
+		if rerr != nil {
+			return summary, fmt.Errorf("error reading CSV at record %d: %w", i+1, rerr)
+		}
+		summary.Processed++
+
+		line, rej := p.process(fields)
+		if rej != nil {
+			p.reject(&summary, i, fields, rej)
+			continue
+		}
+		if _, werr := w.WriteString(line + "\n"); werr != nil {
+			return summary, fmt.Errorf("error writing record %d: %w", i+1, werr)
+		}
+		summary.Written++
+	}
+}
+
+func (p *Processor) runConcurrent(reader *csv.Reader, w *syncWriter) (Summary, error) {
+	type job struct {
+		index  int
+		fields []string
+	}
+	type result struct {
+		index  int
+		fields []string
+		line   string
+		rej    *rejection
+	}
+
+	jobs := make(chan job, p.Workers)
+	results := make(chan result, p.Workers)
+
+	var wg sync.WaitGroup
+	wg.Add(p.Workers)
+	for i := 0; i < p.Workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				line, rej := p.process(j.fields)
+				results <- result{index: j.index, fields: j.fields, line: line, rej: rej}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Workers can finish out of order; reassemble results in input order
+	// before writing so output records stay aligned with their source rows.
+	var summary Summary
+	done := make(chan error, 1)
+	go func() {
+		pending := make(map[int]result)
+		next := 0
+		for res := range results {
+			pending[res.index] = res
+			for {
+				r, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+
+				if r.rej != nil {
+					p.reject(&summary, r.index, r.fields, r.rej)
+					continue
+				}
+				if _, werr := w.WriteString(r.line + "\n"); werr != nil {
+					done <- werr
+					return
+				}
+				summary.Written++
+			}
+		}
+		done <- nil
+	}()
+
+	index := 0
+	var readErr error
+	for {
+		fields, rerr := reader.Read()
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			readErr = fmt.Errorf("error reading CSV at record %d: %w", index+1, rerr)
+			break
+		}
+		summary.Processed++
+		fieldsCopy := append([]string(nil), fields...)
+		jobs <- job{index: index, fields: fieldsCopy}
+		index++
+	}
+	close(jobs)
+
+	if werr := <-done; werr != nil && readErr == nil {
+		readErr = werr
+	}
+	return summary, readErr
+}
+
+func (p *Processor) process(fields []string) (string, *rejection) {
+	record, err := p.Parse(fields)
+	if err != nil {
+		return "", &rejection{rules: []string{"parse_error"}, reason: err.Error()}
+	}
+
+	if p.Validator != nil {
+		if failures := p.Validator.Validate(record); len(failures) > 0 {
+			rules := make([]string, len(failures))
+			reasons := make([]string, len(failures))
+			for i, f := range failures {
+				rules[i] = f.Rule
+				reasons[i] = f.Error()
+			}
+			return "", &rejection{rules: rules, reason: strings.Join(reasons, "; ")}
+		}
+	}
+
+	if p.Transform != nil {
+		record, err = p.Transform(record)
+		if err != nil {
+			return "", &rejection{rules: []string{"transform_error"}, reason: err.Error()}
+		}
+	}
+
+	line, err := p.Format(record)
+	if err != nil {
+		return "", &rejection{rules: []string{"format_error"}, reason: err.Error()}
+	}
+	return line, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// benchCSV builds n rows of the historical 6-column CSV input.
+func benchCSV(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "Doe,Jane,%d Main St,Springfield,IL,62701\n", i)
+	}
+	return b.String()
+}
+
+// benchLayoutSchema is a small fixed-width layout over the historical
+// 6-column address record, used instead of defaultLayoutSchema so the
+// benchmark doesn't depend on the bundled default schema staying stable.
+func benchLayoutSchema() LayoutSchema {
+	return LayoutSchema{
+		RecordLength: 40,
+		Fields: []FieldSpec{
+			{Name: "last_name", Width: 10, Align: "left"},
+			{Name: "first_name", Width: 10, Align: "left"},
+			{Name: "street", Width: 10, Align: "left"},
+			{Name: "city", Width: 4, Align: "left"},
+			{Name: "state", Width: 2, Align: "left"},
+			{Name: "zip", Width: 4, Align: "left"},
+		},
+		Mapping: []ColumnMapping{
+			{Field: "last_name", Index: 0},
+			{Field: "first_name", Index: 1},
+			{Field: "street", Index: 2},
+			{Field: "city", Index: 3},
+			{Field: "state", Index: 4},
+			{Field: "zip", Index: 5},
+		},
+	}
+}
+
+func benchmarkProcessorRun(b *testing.B, workers int) {
+	layout, err := NewLayout(benchLayoutSchema())
+	if err != nil {
+		b.Fatalf("NewLayout: %v", err)
+	}
+	input := benchCSV(1000)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		p := NewProcessor(layout)
+		p.Workers = workers
+		if _, err := p.Run(strings.NewReader(input), &bytes.Buffer{}); err != nil {
+			b.Fatalf("Run: %v", err)
+		}
+	}
+}
+
+func BenchmarkProcessorRunSequential(b *testing.B) {
+	benchmarkProcessorRun(b, 1)
+}
+
+func BenchmarkProcessorRunConcurrent(b *testing.B) {
+	benchmarkProcessorRun(b, 4)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessorRunWithPeriodicFlush(t *testing.T) {
+	layout, err := NewLayout(benchLayoutSchema())
+	if err != nil {
+		t.Fatalf("NewLayout: %v", err)
+	}
+
+	p := NewProcessor(layout)
+	p.FlushInterval = time.Microsecond // flushes concurrently with nearly every write
+
+	var out bytes.Buffer
+	input := benchCSV(2000)
+	summary, err := p.Run(strings.NewReader(input), &out)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if summary.Written != 2000 {
+		t.Errorf("Written = %d, want 2000", summary.Written)
+	}
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 2000 {
+		t.Fatalf("output has %d lines, want 2000", len(lines))
+	}
+	for i, line := range lines {
+		if len(line) != layout.RecordLength() {
+			t.Fatalf("line %d is %d bytes, want %d", i, len(line), layout.RecordLength())
+		}
+		record, err := layout.Parse(line)
+		if err != nil {
+			t.Fatalf("line %d: Parse: %v", i, err)
+		}
+		if record["last_name"] != "Doe" {
+			t.Errorf("line %d: last_name = %q, want %q", i, record["last_name"], "Doe")
+		}
+	}
+}
@@ -3,26 +3,28 @@ package main
 import (
 	"encoding/csv"
 	"fmt"
-	"io"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 )
 
-// AddressRecord represents a single address entry
-type AddressRecord struct {
-	LastName  string
-	FirstName string
-	Street    string
-	City      string
-	State     string
-	Zip       string
-}
-
 // Config holds file paths
 type Config struct {
-	InputFile  string
-	OutputFile string
+	InputFile       string
+	OutputFile      string
+	SchemaFile      string  // optional layout schema (YAML/JSON); falls back to defaultLayoutSchema
+	Encoding        string  // default field encoding, "ebcdic-cp037"; empty means ascii
+	Mode            string  // "csv2fixed" (default) or "fixed2csv"
+	RejectFile      string  // optional; rows failing validation are written here instead of being dropped
+	RejectThreshold float64 // 0 disables; fraction of processed rows that may be rejected before Run fails
+
+	Comma            rune // CSV field delimiter; 0 keeps encoding/csv's default ','
+	Comment          rune // CSV comment character; 0 disables comment lines
+	LazyQuotes       bool // relax RFC 4180 quote handling
+	TrimLeadingSpace bool // trim leading whitespace from each field
+	ReuseRecord      bool // reuse the CSV reader's backing array for zero-allocation reads; forces sequential processing
+	Workers          int  // 0 uses Processor's sequential default; >1 enables the concurrent worker pool
 }
 
 // Default configuration matching COBOL program paths
@@ -31,53 +33,23 @@ var defaultConfig = Config{
 	OutputFile: "/nfs_dir/output/output.txt",
 }
 
-// formatFixedWidth formats an address record into a 160-character fixed-width string
-// matching the COBOL output format:
-// Last Name (25) + Space (5) + First Name (15) + Space (5) + Street (30) +
-// Space (5) + City (15) + Space (5) + State (3) + Space (5) + Zip (10) + Space (38)
-func formatFixedWidth(record AddressRecord) string {
-	var builder strings.Builder
-	builder.Grow(160) // Pre-allocate for efficiency
-
-	// Last name - 25 characters
-	builder.WriteString(padRight(record.LastName, 25))
-	// Filler - 5 spaces
-	builder.WriteString(padRight("", 5))
-	// First name - 15 characters
-	builder.WriteString(padRight(record.FirstName, 15))
-	// Filler - 5 spaces
-	builder.WriteString(padRight("", 5))
-	// Street - 30 characters
-	builder.WriteString(padRight(record.Street, 30))
-	// Filler - 5 spaces
-	builder.WriteString(padRight("", 5))
-	// City - 15 characters
-	builder.WriteString(padRight(record.City, 15))
-	// Filler - 5 spaces
-	builder.WriteString(padRight("", 5))
-	// State - 3 characters
-	builder.WriteString(padRight(record.State, 3))
-	// Filler - 5 spaces
-	builder.WriteString(padRight("", 5))
-	// Zip - 10 characters
-	builder.WriteString(padRight(record.Zip, 10))
-	// Filler - 38 spaces (total = 160 characters)
-	builder.WriteString(padRight("", 38))
-
-	return builder.String()
-}
+// processCSV reads the CSV file and writes formatted fixed-width output
+// using a Processor's streaming parse -> transform -> format -> write
+// pipeline.
+func processCSV(config Config) error {
+	layout, err := loadLayoutOrDefault(config.SchemaFile)
+	if err != nil {
+		return err
+	}
+	if config.Encoding != "" {
+		layout.SetDefaultEncoding(config.Encoding)
+	}
 
-// padRight pads a string with spaces to the right to reach the specified length
-// If the string is longer, it truncates to fit
-func padRight(s string, length int) string {
-	if len(s) >= length {
-		return s[:length]
+	validator, err := NewRecordValidator(layout.schema)
+	if err != nil {
+		return fmt.Errorf("failed to build validator: %w", err)
 	}
-	return s + strings.Repeat(" ", length-len(s))
-}
 
-// processCSV reads the CSV file and writes formatted fixed-width output
-func processCSV(config Config) error {
 	// Open input file
 	inputFile, err := os.Open(config.InputFile)
 	if err != nil {
@@ -92,63 +64,171 @@ func processCSV(config Config) error {
 	}
 	defer outputFile.Close()
 
-	// Create CSV reader
-	csvReader := csv.NewReader(inputFile)
-	csvReader.FieldsPerRecord = 6 // Expect 6 fields per record
-
-	recordCount := 0
+	processor := NewProcessor(layout)
+	processor.Validator = validator
+	processor.RejectThreshold = config.RejectThreshold
+	if config.Comma != 0 {
+		processor.ReaderOptions.Comma = config.Comma
+	}
+	processor.ReaderOptions.Comment = config.Comment
+	processor.ReaderOptions.LazyQuotes = config.LazyQuotes
+	processor.ReaderOptions.TrimLeadingSpace = config.TrimLeadingSpace
+	processor.ReaderOptions.ReuseRecord = config.ReuseRecord
+	if config.Workers > 0 {
+		processor.Workers = config.Workers
+	}
+	processor.OnSkip = func(index int, reason string) {
+		log.Printf("Warning: record %d rejected: %s", index+1, reason)
+	}
 
-	// Read and process each record
-	for {
-		fields, err := csvReader.Read()
-		if err == io.EOF {
-			break
-		}
+	if config.RejectFile != "" {
+		rejectFile, err := os.Create(config.RejectFile)
 		if err != nil {
-			return fmt.Errorf("error reading CSV at record %d: %w", recordCount+1, err)
+			return fmt.Errorf("failed to create reject file: %w", err)
 		}
+		defer rejectFile.Close()
 
-		// Ensure we have exactly 6 fields
-		if len(fields) != 6 {
-			log.Printf("Warning: record %d has %d fields (expected 6), skipping", recordCount+1, len(fields))
-			continue
-		}
+		rejectWriter := csv.NewWriter(rejectFile)
+		defer rejectWriter.Flush()
+		processor.RejectWriter = rejectWriter
+	}
 
-		// Create address record
-		record := AddressRecord{
-			LastName:  strings.TrimSpace(fields[0]),
-			FirstName: strings.TrimSpace(fields[1]),
-			Street:    strings.TrimSpace(fields[2]),
-			City:      strings.TrimSpace(fields[3]),
-			State:     strings.TrimSpace(fields[4]),
-			Zip:       strings.TrimSpace(fields[5]),
-		}
+	summary, err := processor.Run(inputFile, outputFile)
 
-		// Format and write the record
-		formattedLine := formatFixedWidth(record)
-		if _, err := outputFile.WriteString(formattedLine + "\n"); err != nil {
-			return fmt.Errorf("error writing record %d: %w", recordCount+1, err)
-		}
+	log.Printf("Summary: processed=%d written=%d rejected=%d", summary.Processed, summary.Written, summary.Rejected)
+	for rule, count := range summary.RuleCounts {
+		log.Printf("  rejected by %s: %d", rule, count)
+	}
+
+	return err
+}
+
+// loadLayoutOrDefault loads the layout schema at path, or falls back to
+// defaultLayoutSchema when path is empty.
+func loadLayoutOrDefault(path string) (*Layout, error) {
+	if path == "" {
+		return NewLayout(defaultLayoutSchema())
+	}
+	return LoadLayout(path)
+}
 
-		recordCount++
+// parseSingleRune parses a flag value expected to be exactly one character,
+// used for --comma and --comment.
+func parseSingleRune(s string) (rune, error) {
+	runes := []rune(s)
+	if len(runes) != 1 {
+		return 0, fmt.Errorf("expected a single character, got %q", s)
 	}
+	return runes[0], nil
+}
 
-	log.Printf("Successfully processed %d records", recordCount)
-	return nil
+// splitFlags pulls "--name=value" arguments out of args, returning the
+// remaining positional arguments and a name->value map of whatever flags
+// were present.
+func splitFlags(args []string) (positional []string, flags map[string]string) {
+	flags = make(map[string]string)
+	for _, arg := range args {
+		if name, value, ok := strings.Cut(strings.TrimPrefix(arg, "--"), "="); ok && strings.HasPrefix(arg, "--") {
+			flags[name] = value
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	return positional, flags
 }
 
 func main() {
 	// Allow override via command-line arguments
 	config := defaultConfig
-	if len(os.Args) > 2 {
-		config.InputFile = os.Args[1]
-		config.OutputFile = os.Args[2]
+	args, flags := splitFlags(os.Args[1:])
+
+	config.Mode = "csv2fixed"
+	if mode, ok := flags["mode"]; ok {
+		config.Mode = mode
+	}
+	if rejectFile, ok := flags["reject-file"]; ok {
+		config.RejectFile = rejectFile
+	}
+	if thresholdStr, ok := flags["reject-threshold"]; ok {
+		threshold, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil {
+			log.Fatalf("Error: invalid --reject-threshold %q: %v", thresholdStr, err)
+		}
+		config.RejectThreshold = threshold
+	}
+	if commaStr, ok := flags["comma"]; ok {
+		r, err := parseSingleRune(commaStr)
+		if err != nil {
+			log.Fatalf("Error: invalid --comma %q: %v", commaStr, err)
+		}
+		config.Comma = r
+	}
+	if commentStr, ok := flags["comment"]; ok {
+		r, err := parseSingleRune(commentStr)
+		if err != nil {
+			log.Fatalf("Error: invalid --comment %q: %v", commentStr, err)
+		}
+		config.Comment = r
+	}
+	if lazyQuotesStr, ok := flags["lazy-quotes"]; ok {
+		b, err := strconv.ParseBool(lazyQuotesStr)
+		if err != nil {
+			log.Fatalf("Error: invalid --lazy-quotes %q: %v", lazyQuotesStr, err)
+		}
+		config.LazyQuotes = b
+	}
+	if trimStr, ok := flags["trim-leading-space"]; ok {
+		b, err := strconv.ParseBool(trimStr)
+		if err != nil {
+			log.Fatalf("Error: invalid --trim-leading-space %q: %v", trimStr, err)
+		}
+		config.TrimLeadingSpace = b
+	}
+	if reuseStr, ok := flags["reuse-record"]; ok {
+		b, err := strconv.ParseBool(reuseStr)
+		if err != nil {
+			log.Fatalf("Error: invalid --reuse-record %q: %v", reuseStr, err)
+		}
+		config.ReuseRecord = b
+	}
+	if workersStr, ok := flags["workers"]; ok {
+		n, err := strconv.Atoi(workersStr)
+		if err != nil {
+			log.Fatalf("Error: invalid --workers %q: %v", workersStr, err)
+		}
+		config.Workers = n
+	}
+
+	if len(args) > 1 {
+		config.InputFile = args[0]
+		config.OutputFile = args[1]
+	}
+	if len(args) > 2 {
+		config.SchemaFile = args[2]
+	}
+	if len(args) > 3 {
+		config.Encoding = args[3]
+	}
+	if schemaFile, ok := flags["schema"]; ok {
+		config.SchemaFile = schemaFile
+	}
+	if encoding, ok := flags["encoding"]; ok {
+		config.Encoding = encoding
 	}
 
 	log.Printf("Reading from: %s", config.InputFile)
 	log.Printf("Writing to: %s", config.OutputFile)
 
-	if err := processCSV(config); err != nil {
+	var err error
+	switch config.Mode {
+	case "csv2fixed":
+		err = processCSV(config)
+	case "fixed2csv":
+		err = runFixedToCSV(config)
+	default:
+		log.Fatalf("Error: unknown --mode %q (expected csv2fixed or fixed2csv)", config.Mode)
+	}
+	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
 
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// FieldConstraints describes per-field validation rules, evaluated by
+// RecordValidator against a parsed record's field values.
+type FieldConstraints struct {
+	Regex      string   `json:"regex" yaml:"regex"`
+	MinLength  int      `json:"min_length" yaml:"min_length"`
+	MaxLength  int      `json:"max_length" yaml:"max_length"`
+	NumericMin *float64 `json:"numeric_min" yaml:"numeric_min"`
+	NumericMax *float64 `json:"numeric_max" yaml:"numeric_max"`
+	Enum       []string `json:"enum" yaml:"enum"`
+	Format     string   `json:"format" yaml:"format"` // "us_state", "zip5", or "zip9"
+}
+
+// ValidationFailure describes one constraint a field's value did not
+// satisfy.
+type ValidationFailure struct {
+	Field string
+	Rule  string
+	Value string
+}
+
+func (f ValidationFailure) Error() string {
+	return fmt.Sprintf("field %q failed %s check (value %q)", f.Field, f.Rule, f.Value)
+}
+
+var builtinFormats = map[string]*regexp.Regexp{
+	"zip5": regexp.MustCompile(`^\d{5}$`),
+	"zip9": regexp.MustCompile(`^\d{5}-?\d{4}$`),
+}
+
+// usStateCodes is the standard two-letter USPS state/territory abbreviation
+// table, used by the "us_state" built-in format.
+var usStateCodes = map[string]bool{
+	"AL": true, "AK": true, "AZ": true, "AR": true, "CA": true, "CO": true,
+	"CT": true, "DE": true, "FL": true, "GA": true, "HI": true, "ID": true,
+	"IL": true, "IN": true, "IA": true, "KS": true, "KY": true, "LA": true,
+	"ME": true, "MD": true, "MA": true, "MI": true, "MN": true, "MS": true,
+	"MO": true, "MT": true, "NE": true, "NV": true, "NH": true, "NJ": true,
+	"NM": true, "NY": true, "NC": true, "ND": true, "OH": true, "OK": true,
+	"OR": true, "PA": true, "RI": true, "SC": true, "SD": true, "TN": true,
+	"TX": true, "UT": true, "VT": true, "VA": true, "WA": true, "WV": true,
+	"WI": true, "WY": true, "DC": true, "PR": true, "VI": true, "GU": true,
+	"AS": true, "MP": true,
+}
+
+type fieldValidator struct {
+	name   string
+	c      FieldConstraints
+	regex  *regexp.Regexp
+	enum   map[string]bool
+	format *regexp.Regexp
+}
+
+// RecordValidator runs each field's FieldConstraints against a parsed
+// record and reports every failure, not just the first.
+type RecordValidator struct {
+	fields []fieldValidator
+}
+
+// NewRecordValidator compiles every field's Constraints in schema into a
+// RecordValidator. Fields without Constraints are not checked.
+func NewRecordValidator(schema LayoutSchema) (*RecordValidator, error) {
+	var fields []fieldValidator
+	for _, f := range schema.Fields {
+		if f.Constraints == nil {
+			continue
+		}
+		fv := fieldValidator{name: f.Name, c: *f.Constraints}
+
+		if f.Constraints.Regex != "" {
+			re, err := regexp.Compile(f.Constraints.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: invalid regex %q: %w", f.Name, f.Constraints.Regex, err)
+			}
+			fv.regex = re
+		}
+
+		if len(f.Constraints.Enum) > 0 {
+			fv.enum = make(map[string]bool, len(f.Constraints.Enum))
+			for _, v := range f.Constraints.Enum {
+				fv.enum[v] = true
+			}
+		}
+
+		switch f.Constraints.Format {
+		case "", "us_state":
+			// us_state is checked directly against usStateCodes, no regexp needed
+		case "zip5", "zip9":
+			fv.format = builtinFormats[f.Constraints.Format]
+		default:
+			return nil, fmt.Errorf("field %q: unknown format %q", f.Name, f.Constraints.Format)
+		}
+
+		fields = append(fields, fv)
+	}
+	return &RecordValidator{fields: fields}, nil
+}
+
+// Validate runs every configured constraint against record, returning all
+// failures found (nil if the record is clean).
+func (v *RecordValidator) Validate(record map[string]string) []ValidationFailure {
+	var failures []ValidationFailure
+	for _, fv := range v.fields {
+		value := record[fv.name]
+		c := fv.c
+
+		if c.MinLength > 0 && len(value) < c.MinLength {
+			failures = append(failures, ValidationFailure{fv.name, "min_length", value})
+		}
+		if c.MaxLength > 0 && len(value) > c.MaxLength {
+			failures = append(failures, ValidationFailure{fv.name, "max_length", value})
+		}
+		if fv.regex != nil && !fv.regex.MatchString(value) {
+			failures = append(failures, ValidationFailure{fv.name, "regex", value})
+		}
+		if fv.enum != nil && !fv.enum[value] {
+			failures = append(failures, ValidationFailure{fv.name, "enum", value})
+		}
+		if c.NumericMin != nil || c.NumericMax != nil {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				failures = append(failures, ValidationFailure{fv.name, "numeric_range", value})
+			} else {
+				if c.NumericMin != nil && n < *c.NumericMin {
+					failures = append(failures, ValidationFailure{fv.name, "numeric_range", value})
+				}
+				if c.NumericMax != nil && n > *c.NumericMax {
+					failures = append(failures, ValidationFailure{fv.name, "numeric_range", value})
+				}
+			}
+		}
+		switch c.Format {
+		case "us_state":
+			if !usStateCodes[value] {
+				failures = append(failures, ValidationFailure{fv.name, "us_state", value})
+			}
+		case "zip5", "zip9":
+			if fv.format != nil && !fv.format.MatchString(value) {
+				failures = append(failures, ValidationFailure{fv.name, c.Format, value})
+			}
+		}
+	}
+	return failures
+}
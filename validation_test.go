@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func float64Ptr(f float64) *float64 { return &f }
+
+func TestRecordValidatorChecks(t *testing.T) {
+	schema := LayoutSchema{
+		Fields: []FieldSpec{
+			{Name: "state", Width: 2, Constraints: &FieldConstraints{Format: "us_state"}},
+			{Name: "zip", Width: 5, Constraints: &FieldConstraints{Format: "zip5"}},
+			{Name: "code", Width: 4, Constraints: &FieldConstraints{Regex: `^[A-Z]{4}$`}},
+			{Name: "status", Width: 1, Constraints: &FieldConstraints{Enum: []string{"A", "I"}}},
+			{Name: "amount", Width: 6, Constraints: &FieldConstraints{NumericMin: float64Ptr(0), NumericMax: float64Ptr(100)}},
+			{Name: "name", Width: 10, Constraints: &FieldConstraints{MinLength: 2, MaxLength: 5}},
+		},
+	}
+	v, err := NewRecordValidator(schema)
+	if err != nil {
+		t.Fatalf("NewRecordValidator: %v", err)
+	}
+
+	clean := map[string]string{
+		"state": "IL", "zip": "62701", "code": "ABCD",
+		"status": "A", "amount": "50", "name": "Doe",
+	}
+	if failures := v.Validate(clean); len(failures) != 0 {
+		t.Errorf("Validate(clean) = %v, want no failures", failures)
+	}
+
+	cases := []struct {
+		name  string
+		field string
+		value string
+		rule  string
+	}{
+		{"bad us_state", "state", "ZZ", "us_state"},
+		{"bad zip5", "zip", "abc", "zip5"},
+		{"bad regex", "code", "abcd", "regex"},
+		{"bad enum", "status", "X", "enum"},
+		{"numeric below min", "amount", "-1", "numeric_range"},
+		{"numeric above max", "amount", "101", "numeric_range"},
+		{"non-numeric value", "amount", "n/a", "numeric_range"},
+		{"too short", "name", "D", "min_length"},
+		{"too long", "name", "TooLongName", "max_length"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			record := make(map[string]string, len(clean))
+			for k, v := range clean {
+				record[k] = v
+			}
+			record[c.field] = c.value
+
+			failures := v.Validate(record)
+			found := false
+			for _, f := range failures {
+				if f.Field == c.field && f.Rule == c.rule {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Validate(%v) = %v, want a %q failure on field %q", record, failures, c.rule, c.field)
+			}
+		})
+	}
+}
+
+func TestNewRecordValidatorRejectsInvalidRegexAndFormat(t *testing.T) {
+	if _, err := NewRecordValidator(LayoutSchema{Fields: []FieldSpec{
+		{Name: "f", Width: 1, Constraints: &FieldConstraints{Regex: "("}},
+	}}); err == nil {
+		t.Error("NewRecordValidator with an invalid regex should fail")
+	}
+	if _, err := NewRecordValidator(LayoutSchema{Fields: []FieldSpec{
+		{Name: "f", Width: 1, Constraints: &FieldConstraints{Format: "postal_code"}},
+	}}); err == nil {
+		t.Error("NewRecordValidator with an unknown Format should fail")
+	}
+}
+
+func TestProcessCSVRoutesRejectsAndReportsThreshold(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "schema.json")
+	const schemaDoc = `{
+		"record_length": 7,
+		"fields": [
+			{"name": "state", "width": 2},
+			{"name": "zip", "width": 5, "constraints": {"format": "zip5"}}
+		],
+		"mapping": [
+			{"field": "state", "index": 0},
+			{"field": "zip", "index": 1}
+		]
+	}`
+	if err := os.WriteFile(schemaPath, []byte(schemaDoc), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	inputPath := filepath.Join(dir, "in.csv")
+	const input = "IL,62701\nCA,bad\n"
+	if err := os.WriteFile(inputPath, []byte(input), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	outputPath := filepath.Join(dir, "out.txt")
+	rejectPath := filepath.Join(dir, "reject.csv")
+
+	err := processCSV(Config{
+		InputFile:       inputPath,
+		OutputFile:      outputPath,
+		SchemaFile:      schemaPath,
+		RejectFile:      rejectPath,
+		RejectThreshold: 0.1, // 1 of 2 rows rejected (0.5) exceeds this
+	})
+	if !errors.Is(err, ErrRejectThresholdExceeded) {
+		t.Fatalf("processCSV error = %v, want ErrRejectThresholdExceeded", err)
+	}
+
+	written, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("ReadFile output: %v", err)
+	}
+	if string(written) != "IL62701\n" {
+		t.Errorf("output = %q, want the one valid record only", written)
+	}
+
+	rejected, err := os.ReadFile(rejectPath)
+	if err != nil {
+		t.Fatalf("ReadFile reject file: %v", err)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(rejected)).ReadAll()
+	if err != nil {
+		t.Fatalf("parsing reject file: %v", err)
+	}
+	if len(rows) != 1 || rows[0][0] != "CA" || rows[0][1] != "bad" {
+		t.Errorf("reject file rows = %v, want the CA,bad row plus a reason column", rows)
+	}
+}